@@ -1,11 +1,26 @@
 package buttons
 
+import (
+	"fmt"
+	"strings"
+
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
 var (
 	ApproveButton = New("✔️ Approve")
 	SkipButton    = New("👀 Skip")
 	RejectButton  = New("❌ Reject")
 )
 
+// Opcodes kept short so CallbackData (opcode + ":" + record ID) stays well
+// under Telegram's 64-byte callback data limit.
+const (
+	OpApprove = "a"
+	OpReject  = "r"
+	OpSkip    = "s"
+)
+
 type Button struct {
 	text string
 }
@@ -19,3 +34,61 @@ func New(text string) Button {
 func (b Button) Text() string {
 	return b.text
 }
+
+// CallbackData encodes op and recordID into a single CallbackData payload.
+func CallbackData(op, recordID string) string {
+	return op + ":" + recordID
+}
+
+// ParseCallbackData decodes a payload produced by CallbackData. It rejects
+// anything whose op isn't one of the known opcodes, since CallbackData is
+// user-controlled once it leaves Telegram as a CallbackQuery and nothing
+// guarantees it still matches a button this bot sent.
+func ParseCallbackData(data string) (op, recordID string, ok bool) {
+	op, recordID, found := strings.Cut(data, ":")
+	if !found || recordID == "" || !isKnownOp(op) {
+		return "", "", false
+	}
+	return op, recordID, true
+}
+
+func isKnownOp(op string) bool {
+	switch op {
+	case OpApprove, OpReject, OpSkip:
+		return true
+	default:
+		return false
+	}
+}
+
+// PostKeyboard builds the inline keyboard offered alongside a post, with
+// each button's CallbackData encoding both the action and recordID so the
+// same post can be reviewed independently of whatever else is pending.
+func PostKeyboard(recordID string) tgbotapi.InlineKeyboardMarkup {
+	return tgbotapi.NewInlineKeyboardMarkup(
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(ApproveButton.Text(), CallbackData(OpApprove, recordID)),
+			tgbotapi.NewInlineKeyboardButtonData(RejectButton.Text(), CallbackData(OpReject, recordID)),
+		),
+		tgbotapi.NewInlineKeyboardRow(
+			tgbotapi.NewInlineKeyboardButtonData(SkipButton.Text(), CallbackData(OpSkip, recordID)),
+		),
+	)
+}
+
+// DecisionLabel returns the human-readable label for op, as used both on
+// buttons and when annotating a decided message. It panics on an unknown
+// op since that can only mean CallbackData and ParseCallbackData drifted
+// apart.
+func DecisionLabel(op string) string {
+	switch op {
+	case OpApprove:
+		return ApproveButton.Text()
+	case OpReject:
+		return RejectButton.Text()
+	case OpSkip:
+		return SkipButton.Text()
+	default:
+		panic(fmt.Sprintf("buttons: unknown opcode %q", op))
+	}
+}
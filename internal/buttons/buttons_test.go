@@ -0,0 +1,49 @@
+package buttons
+
+import "testing"
+
+func TestParseCallbackData(t *testing.T) {
+	tests := []struct {
+		name         string
+		data         string
+		wantOp       string
+		wantRecordID string
+		wantOK       bool
+	}{
+		{"approve", CallbackData(OpApprove, "rec123"), OpApprove, "rec123", true},
+		{"reject", CallbackData(OpReject, "rec123"), OpReject, "rec123", true},
+		{"skip", CallbackData(OpSkip, "rec123"), OpSkip, "rec123", true},
+		{"unknown opcode", "x:rec123", "", "", false},
+		{"no separator", "arec123", "", "", false},
+		{"empty recordID", "a:", "", "", false},
+		{"empty string", "", "", "", false},
+		{"recordID with colon", CallbackData(OpApprove, "rec:123"), OpApprove, "rec:123", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			op, recordID, ok := ParseCallbackData(tt.data)
+			if ok != tt.wantOK || op != tt.wantOp || recordID != tt.wantRecordID {
+				t.Errorf("ParseCallbackData(%q) = (%q, %q, %v), want (%q, %q, %v)",
+					tt.data, op, recordID, ok, tt.wantOp, tt.wantRecordID, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestDecisionLabelKnownOps(t *testing.T) {
+	for _, op := range []string{OpApprove, OpReject, OpSkip} {
+		if label := DecisionLabel(op); label == "" {
+			t.Errorf("DecisionLabel(%q) returned empty label", op)
+		}
+	}
+}
+
+func TestDecisionLabelUnknownOpPanics(t *testing.T) {
+	defer func() {
+		if recover() == nil {
+			t.Error("DecisionLabel(\"x\") did not panic")
+		}
+	}()
+	DecisionLabel("x")
+}
@@ -13,6 +13,8 @@ type Env struct {
 	AirtableApiKey    string
 	AirtableBaseId    string
 	AirtableTableName string
+	StorePath         string
+	PreviewEnabled    bool
 }
 
 func New() *Env {
@@ -44,5 +46,10 @@ func New() *Env {
 	if env.AirtableTableName == "" {
 		log.Fatalf("AIRTABLE_TABLE_NAME is not set")
 	}
+	env.StorePath = os.Getenv("STORE_PATH")
+	if env.StorePath == "" {
+		env.StorePath = "decisions.db"
+	}
+	env.PreviewEnabled, _ = strconv.ParseBool(os.Getenv("PREVIEW_ENABLED"))
 	return env
 }
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"fmt"
+	"html"
+	"time"
+
+	"github.com/IShamraI/post-approver-bot/internal/buttons"
+	"github.com/IShamraI/post-approver-bot/internal/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+)
+
+// decisionFields are the Airtable fields to set for each opcode; skip
+// leaves the record untouched.
+var decisionFields = map[string]map[string]any{
+	buttons.OpApprove: {"IsApproved": true, "IsRejected": false, "ToInvistigate": false},
+	buttons.OpReject:  {"IsRejected": true, "IsApproved": false, "ToInvistigate": false},
+}
+
+var decisionByOp = map[string]store.Decision{
+	buttons.OpApprove: store.DecisionApprove,
+	buttons.OpReject:  store.DecisionReject,
+	buttons.OpSkip:    store.DecisionSkip,
+}
+
+// operatorName identifies who pressed a button, for the decided-message annotation.
+func operatorName(user *tgbotapi.User) string {
+	if user == nil {
+		return "unknown"
+	}
+	if user.UserName != "" {
+		return "@" + user.UserName
+	}
+	return user.FirstName
+}
+
+// HandleCallback decodes a post review button press, applies the decision
+// to Airtable, records it, and edits the original message to show what
+// happened instead of leaving it as a live "current post".
+func HandleCallback(ctx *Context) error {
+	cq := ctx.Update.CallbackQuery
+
+	op, recordID, ok := buttons.ParseCallbackData(cq.Data)
+	if !ok {
+		return ctx.answerCallback(cq.ID, "Некорректные данные кнопки")
+	}
+
+	offer, has := ctx.Sessions.Offer(recordID)
+	if !has {
+		return ctx.answerCallback(cq.ID, "Этот пост уже обработан")
+	}
+
+	if fields, ok := decisionFields[op]; ok {
+		if _, err := offer.Record.UpdateRecordPartial(fields); err != nil {
+			return fmt.Errorf("update record: %w", err)
+		}
+	}
+
+	guid, _ := offer.Record.Fields["guid"].(string)
+	if err := ctx.Decisions.Record(guid, offer.ChatID, decisionByOp[op], time.Now()); err != nil {
+		return fmt.Errorf("record decision: %w", err)
+	}
+	ctx.Sessions.ClearOffer(recordID)
+
+	decidedText := fmt.Sprintf("<s>%s</s>\n\n%s %s", html.EscapeString(PostText(offer.Record)), buttons.DecisionLabel(op), html.EscapeString(operatorName(cq.From)))
+	edit := tgbotapi.NewEditMessageText(offer.ChatID, offer.MessageID, decidedText)
+	edit.ParseMode = tgbotapi.ModeHTML
+	if _, err := ctx.Bot.Send(edit); err != nil {
+		return fmt.Errorf("edit message: %w", err)
+	}
+
+	return ctx.answerCallback(cq.ID, buttons.DecisionLabel(op))
+}
+
+func (c *Context) answerCallback(callbackID, text string) error {
+	_, err := c.Bot.Request(tgbotapi.NewCallback(callbackID, text))
+	return err
+}
@@ -0,0 +1,110 @@
+package handlers
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IShamraI/post-approver-bot/internal/buttons"
+	"github.com/IShamraI/post-approver-bot/internal/preview"
+	"github.com/IShamraI/post-approver-bot/internal/session"
+	"github.com/IShamraI/post-approver-bot/internal/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mehanizm/airtable"
+)
+
+// SelectPost returns the first post in table's review view that has
+// neither a recorded decision nor a pending offer, or nil if there isn't one.
+func SelectPost(table *airtable.Table, decisions *store.Store, sessions *session.Store) (*airtable.Record, error) {
+	records, err := table.GetRecords().
+		FromView("view_1").
+		WithFilterFormula("AND({ToInvistigate} = 0, {IsApproved} = 0, {IsRejected} = 0)").
+		ReturnFields("Title", "guid").
+		InStringFormat("Europe/Moscow", "ru").
+		Do()
+	if err != nil {
+		return nil, fmt.Errorf("fetch posts: %w", err)
+	}
+
+	for i, record := range records.Records {
+		guid, ok := record.Fields["guid"].(string)
+		if !ok {
+			log.Printf("getpost: record %s has no string guid, skipping", record.ID)
+			continue
+		}
+		decided, err := decisions.Has(guid)
+		if err != nil {
+			return nil, fmt.Errorf("check decision for %s: %w", guid, err)
+		}
+		if decided || sessions.HasOffer(record.ID) {
+			continue
+		}
+		return records.Records[i], nil
+	}
+	return nil, nil
+}
+
+// PostText renders the message text a post is offered with.
+func PostText(post *airtable.Record) string {
+	return fmt.Sprintf("Пост: %s\n%s", post.Fields["Title"], post.Fields["guid"])
+}
+
+// renderPreview returns the rendered article preview for guid, using
+// decisions as a cache so a post whose preview was rendered and cached is
+// not fetched again; a failed render is not cached. It returns ""
+// (and logs) rather than failing the offer if fetching goes wrong.
+func renderPreview(decisions *store.Store, fetcher *preview.Fetcher, guid string) string {
+	if fetcher == nil {
+		return ""
+	}
+	if cached, ok, err := decisions.CachedPreview(guid); err == nil && ok {
+		return cached
+	}
+	text, err := fetcher.Render(guid)
+	if err != nil {
+		log.Printf("preview: render %s: %s", guid, err)
+		return ""
+	}
+	if err := decisions.CachePreview(guid, text, time.Now()); err != nil {
+		log.Printf("preview: cache %s: %s", guid, err)
+	}
+	return text
+}
+
+// SendPost sends post to chatID with its review keyboard and records the
+// resulting message as a pending offer. fetcher may be nil, in which case
+// no preview is fetched.
+func SendPost(bot *tgbotapi.BotAPI, sessions *session.Store, decisions *store.Store, fetcher *preview.Fetcher, chatID int64, post *airtable.Record) error {
+	text := PostText(post)
+	if guid, ok := post.Fields["guid"].(string); ok {
+		if previewText := renderPreview(decisions, fetcher, guid); previewText != "" {
+			// Reserve the header plus the "\n\n" separator so the combined
+			// message, not just the preview, stays within Telegram's limit.
+			previewText = preview.Truncate(previewText, guid, len(text)+2)
+			text = fmt.Sprintf("%s\n\n%s", text, previewText)
+		}
+	}
+
+	msg := tgbotapi.NewMessage(chatID, text)
+	msg.ReplyMarkup = buttons.PostKeyboard(post.ID)
+	sent, err := bot.Send(msg)
+	if err != nil {
+		return fmt.Errorf("send post: %w", err)
+	}
+	sessions.SetOffer(chatID, post, sent.MessageID)
+	return nil
+}
+
+// GetPost offers the requesting chat the next post that has no recorded
+// decision yet.
+func GetPost(ctx *Context) error {
+	post, err := SelectPost(ctx.Table, ctx.Decisions, ctx.Sessions)
+	if err != nil {
+		return err
+	}
+	if post == nil {
+		return ctx.Reply("Нет новых постов", nil)
+	}
+
+	return SendPost(ctx.Bot, ctx.Sessions, ctx.Decisions, ctx.Preview, ctx.ChatID(), post)
+}
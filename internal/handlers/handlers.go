@@ -0,0 +1,93 @@
+// Package handlers maps incoming commands and callback button presses to
+// the functions that serve them, so adding a new one does not mean editing
+// a growing switch statement in main.
+package handlers
+
+import (
+	"log"
+	"time"
+
+	"github.com/IShamraI/post-approver-bot/internal/preview"
+	"github.com/IShamraI/post-approver-bot/internal/session"
+	"github.com/IShamraI/post-approver-bot/internal/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mehanizm/airtable"
+)
+
+// Subscriptions is the push-mode surface handlers need; it is satisfied by
+// *subscriptions.Manager, kept as an interface here to avoid an import
+// cycle (the subscriptions package itself calls into handlers).
+type Subscriptions interface {
+	Subscribe(chatID int64, interval time.Duration) error
+	Unsubscribe(chatID int64) error
+	Pause(chatID int64) error
+	Resume(chatID int64) error
+}
+
+// Context bundles everything a handler needs to serve one update.
+type Context struct {
+	Bot           *tgbotapi.BotAPI
+	Update        tgbotapi.Update
+	Sessions      *session.Store
+	Decisions     *store.Store
+	Table         *airtable.Table
+	Subscriptions Subscriptions
+	Preview       *preview.Fetcher // nil when PREVIEW_ENABLED is false
+	Logger        *log.Logger
+}
+
+// ChatID returns the chat the current update came from.
+func (c *Context) ChatID() int64 {
+	return c.Update.FromChat().ID
+}
+
+// Reply sends text back to the chat the update came from, optionally with
+// a reply markup.
+func (c *Context) Reply(text string, markup interface{}) error {
+	msg := tgbotapi.NewMessage(c.ChatID(), text)
+	if markup != nil {
+		msg.ReplyMarkup = markup
+	}
+	_, err := c.Bot.Send(msg)
+	return err
+}
+
+// Func is the signature every command handler implements.
+type Func func(ctx *Context) error
+
+// Router maps command names to the Func that serves them. Button presses
+// no longer go through Router: they arrive as callback queries and are
+// handled directly by HandleCallback.
+type Router struct {
+	commands       map[string]Func
+	unknownCommand Func
+}
+
+// NewRouter creates an empty Router. UnknownCommand must be set before
+// Dispatch is called against commands that don't match a registered one.
+func NewRouter() *Router {
+	return &Router{
+		commands: make(map[string]Func),
+	}
+}
+
+// HandleCommand registers fn to serve the /name command.
+func (r *Router) HandleCommand(name string, fn Func) {
+	r.commands[name] = fn
+}
+
+// UnknownCommand registers the fallback for commands with no handler.
+func (r *Router) UnknownCommand(fn Func) {
+	r.unknownCommand = fn
+}
+
+// Dispatch routes ctx.Update's command to the matching handler and runs
+// it. A single handler returning an error no longer takes the whole bot
+// down with it; the caller is expected to report it to the user.
+func (r *Router) Dispatch(ctx *Context) error {
+	fn, ok := r.commands[ctx.Update.Message.Command()]
+	if !ok {
+		fn = r.unknownCommand
+	}
+	return fn(ctx)
+}
@@ -0,0 +1,8 @@
+package handlers
+
+// Help replies with a fixed help message. The message names only /sayhi
+// and /status; it does not list the commands registered in newRouter, and
+// /sayhi has no handler of its own.
+func Help(ctx *Context) error {
+	return ctx.Reply("I understand /sayhi and /status.", nil)
+}
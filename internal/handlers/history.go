@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// History replies with the requesting operator's last 10 decisions.
+func History(ctx *Context) error {
+	recent, err := ctx.Decisions.RecentDecisions(ctx.ChatID(), 10)
+	if err != nil {
+		return fmt.Errorf("load history: %w", err)
+	}
+	if len(recent) == 0 {
+		return ctx.Reply("Решений пока нет", nil)
+	}
+
+	var b strings.Builder
+	for _, r := range recent {
+		fmt.Fprintf(&b, "%s — %s (%s)\n", r.GUID, r.Decision, r.DecidedAt.Format(time.RFC3339))
+	}
+	return ctx.Reply(b.String(), nil)
+}
@@ -0,0 +1,11 @@
+package handlers
+
+import "fmt"
+
+// Pause temporarily stops the requesting chat's push subscription.
+func Pause(ctx *Context) error {
+	if err := ctx.Subscriptions.Pause(ctx.ChatID()); err != nil {
+		return fmt.Errorf("pause subscription: %w", err)
+	}
+	return ctx.Reply("Подписка приостановлена", nil)
+}
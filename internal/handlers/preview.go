@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"strings"
+
+	"github.com/IShamraI/post-approver-bot/internal/preview"
+)
+
+// Preview renders the article behind an arbitrary guid on demand, e.g.
+// "/preview https://example.com/article". Unlike GetPost, it works on any
+// guid and does not offer the post for review.
+func Preview(ctx *Context) error {
+	guid := strings.TrimSpace(ctx.Update.Message.CommandArguments())
+	if guid == "" {
+		return ctx.Reply("Укажите guid, например /preview https://example.com/article", nil)
+	}
+	if ctx.Preview == nil {
+		return ctx.Reply("Превью постов отключено", nil)
+	}
+
+	text := renderPreview(ctx.Decisions, ctx.Preview, guid)
+	if text == "" {
+		return ctx.Reply("Не удалось получить превью для этого guid", nil)
+	}
+	return ctx.Reply(preview.Truncate(text, guid, 0), nil)
+}
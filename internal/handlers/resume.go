@@ -0,0 +1,11 @@
+package handlers
+
+import "fmt"
+
+// Resume undoes Pause for the requesting chat's push subscription.
+func Resume(ctx *Context) error {
+	if err := ctx.Subscriptions.Resume(ctx.ChatID()); err != nil {
+		return fmt.Errorf("resume subscription: %w", err)
+	}
+	return ctx.Reply("Подписка возобновлена", nil)
+}
@@ -0,0 +1,6 @@
+package handlers
+
+// Start greets the operator on /start.
+func Start(ctx *Context) error {
+	return ctx.Reply("Hi!", nil)
+}
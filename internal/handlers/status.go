@@ -0,0 +1,6 @@
+package handlers
+
+// Status is a liveness check for /status.
+func Status(ctx *Context) error {
+	return ctx.Reply("I'm ok.", nil)
+}
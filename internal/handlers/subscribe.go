@@ -0,0 +1,24 @@
+package handlers
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Subscribe starts pushing un-reviewed posts to the requesting chat every
+// interval, e.g. "/subscribe 10m".
+func Subscribe(ctx *Context) error {
+	arg := strings.TrimSpace(ctx.Update.Message.CommandArguments())
+	if arg == "" {
+		return ctx.Reply("Укажите интервал, например /subscribe 10m", nil)
+	}
+	interval, err := time.ParseDuration(arg)
+	if err != nil {
+		return ctx.Reply(fmt.Sprintf("Не удалось разобрать интервал %q: %s", arg, err), nil)
+	}
+	if err := ctx.Subscriptions.Subscribe(ctx.ChatID(), interval); err != nil {
+		return fmt.Errorf("subscribe: %w", err)
+	}
+	return ctx.Reply(fmt.Sprintf("Подписка оформлена, интервал %s", interval), nil)
+}
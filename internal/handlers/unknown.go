@@ -0,0 +1,6 @@
+package handlers
+
+// UnknownCommand replies when a command has no registered handler.
+func UnknownCommand(ctx *Context) error {
+	return ctx.Reply("I don't know that command", nil)
+}
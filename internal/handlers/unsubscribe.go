@@ -0,0 +1,11 @@
+package handlers
+
+import "fmt"
+
+// Unsubscribe stops pushing posts to the requesting chat.
+func Unsubscribe(ctx *Context) error {
+	if err := ctx.Subscriptions.Unsubscribe(ctx.ChatID()); err != nil {
+		return fmt.Errorf("unsubscribe: %w", err)
+	}
+	return ctx.Reply("Подписка отменена", nil)
+}
@@ -0,0 +1,11 @@
+package helpers
+
+// IDContains reports whether id is present in ids.
+func IDContains(ids []int64, id int64) bool {
+	for _, v := range ids {
+		if v == id {
+			return true
+		}
+	}
+	return false
+}
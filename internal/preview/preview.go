@@ -0,0 +1,255 @@
+// Package preview fetches the article behind a post's guid URL and
+// renders it down to plain text an operator can actually review without
+// leaving Telegram.
+package preview
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+const (
+	// TelegramMessageLimit is the maximum length of a Telegram message.
+	TelegramMessageLimit = 4096
+	// DefaultTimeout is a sensible default for Fetcher.Client's timeout.
+	DefaultTimeout = 10 * time.Second
+	// DefaultMaxBytes caps how much of a response body Render reads.
+	DefaultMaxBytes = 2 << 20
+)
+
+// Fetcher downloads and renders article previews.
+type Fetcher struct {
+	Client    *http.Client
+	MaxBytes  int64
+	UserAgent string
+}
+
+// NewFetcher builds a Fetcher with the given request timeout, response
+// size cap, and User-Agent.
+func NewFetcher(timeout time.Duration, maxBytes int64, userAgent string) *Fetcher {
+	return &Fetcher{
+		Client:    &http.Client{Timeout: timeout},
+		MaxBytes:  maxBytes,
+		UserAgent: userAgent,
+	}
+}
+
+// Render fetches rawURL and returns a plain text rendering of its main
+// content. The result is not truncated: callers know how much of
+// TelegramMessageLimit they have left after adding their own header, so
+// truncation happens via Truncate once the final message is assembled.
+func (f *Fetcher) Render(rawURL string) (string, error) {
+	if err := checkFetchableURL(rawURL); err != nil {
+		return "", fmt.Errorf("reject %s: %w", rawURL, err)
+	}
+
+	req, err := http.NewRequest(http.MethodGet, rawURL, nil)
+	if err != nil {
+		return "", fmt.Errorf("build request for %s: %w", rawURL, err)
+	}
+	req.Header.Set("User-Agent", f.UserAgent)
+
+	client := *f.Client
+	client.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+		return checkFetchableURL(req.URL.String())
+	}
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("fetch %s: %w", rawURL, err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("fetch %s: unexpected status %s", rawURL, resp.Status)
+	}
+
+	doc, err := html.Parse(io.LimitReader(resp.Body, f.MaxBytes))
+	if err != nil {
+		return "", fmt.Errorf("parse %s: %w", rawURL, err)
+	}
+
+	text := collapseWhitespace(extractText(findBody(doc)))
+	return text, nil
+}
+
+// checkFetchableURL rejects anything that isn't a plain http(s) request to
+// a public host, so a post's guid field (or an operator-typed /preview
+// argument) can't turn Render into an SSRF probe of the bot's own
+// infrastructure (loopback, link-local, cloud metadata, internal services).
+// The host is resolved here, before the request and on each redirect; the
+// connection the client then opens resolves it again, and that address is
+// not checked.
+func checkFetchableURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return fmt.Errorf("parse url: %w", err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return fmt.Errorf("unsupported scheme %q", u.Scheme)
+	}
+
+	host := u.Hostname()
+	ips, err := net.LookupIP(host)
+	if err != nil {
+		return fmt.Errorf("resolve host %q: %w", host, err)
+	}
+	for _, ip := range ips {
+		if isDisallowedIP(ip) {
+			return fmt.Errorf("host %q resolves to disallowed address %s", host, ip)
+		}
+	}
+	return nil
+}
+
+// isDisallowedIP reports whether ip is a loopback, link-local, private, or
+// unspecified address. Other special-purpose ranges (multicast beyond
+// link-local, shared address space and the like) are not rejected.
+func isDisallowedIP(ip net.IP) bool {
+	return ip.IsLoopback() ||
+		ip.IsLinkLocalUnicast() ||
+		ip.IsLinkLocalMulticast() ||
+		ip.IsPrivate() ||
+		ip.IsUnspecified()
+}
+
+// findBody returns doc's <body> node, or doc itself if there isn't one.
+func findBody(doc *html.Node) *html.Node {
+	var find func(*html.Node) *html.Node
+	find = func(n *html.Node) *html.Node {
+		if n.Type == html.ElementNode && n.Data == "body" {
+			return n
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			if body := find(c); body != nil {
+				return body
+			}
+		}
+		return nil
+	}
+	if body := find(doc); body != nil {
+		return body
+	}
+	return doc
+}
+
+// blockTags start a new paragraph once their content has been walked.
+var blockTags = map[string]bool{
+	"p": true, "div": true, "br": true, "li": true, "tr": true,
+	"h1": true, "h2": true, "h3": true, "h4": true, "h5": true, "h6": true,
+}
+
+// skippedTags contribute nothing to the rendered text.
+var skippedTags = map[string]bool{
+	"script": true, "style": true, "head": true, "img": true, "noscript": true,
+}
+
+// extractText walks n's subtree rendering paragraphs as plain text, links
+// as "text (url)", and dropping images and non-visible elements.
+func extractText(n *html.Node) string {
+	var b strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			return
+		}
+		if n.Type != html.ElementNode {
+			return
+		}
+		if skippedTags[n.Data] {
+			return
+		}
+		if n.Data == "a" {
+			b.WriteString(renderLink(n))
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+		if blockTags[n.Data] {
+			b.WriteString("\n\n")
+		}
+	}
+	walk(n)
+	return b.String()
+}
+
+func renderLink(a *html.Node) string {
+	var text strings.Builder
+	var walk func(*html.Node)
+	walk = func(n *html.Node) {
+		if n.Type == html.TextNode {
+			text.WriteString(n.Data)
+			return
+		}
+		for c := n.FirstChild; c != nil; c = c.NextSibling {
+			walk(c)
+		}
+	}
+	walk(a)
+
+	label := strings.TrimSpace(text.String())
+	href := attr(a, "href")
+	switch {
+	case href == "":
+		return label
+	case label == "":
+		return href
+	default:
+		return fmt.Sprintf("%s (%s)", label, href)
+	}
+}
+
+func attr(n *html.Node, key string) string {
+	for _, a := range n.Attr {
+		if a.Key == key {
+			return a.Val
+		}
+	}
+	return ""
+}
+
+var (
+	inlineSpaceRE = regexp.MustCompile(`[ \t]+`)
+	blankLinesRE  = regexp.MustCompile(`\n{3,}`)
+)
+
+// collapseWhitespace trims each line and squeezes runs of blank lines down
+// to a single paragraph break.
+func collapseWhitespace(s string) string {
+	lines := strings.Split(s, "\n")
+	for i, line := range lines {
+		lines[i] = strings.TrimSpace(inlineSpaceRE.ReplaceAllString(line, " "))
+	}
+	collapsed := blankLinesRE.ReplaceAllString(strings.Join(lines, "\n"), "\n\n")
+	return strings.TrimSpace(collapsed)
+}
+
+// Truncate cuts text down to fit within TelegramMessageLimit once reserve
+// bytes (e.g. a header the caller will prepend) are accounted for, leaving
+// room for an ellipsis and a "read more" link to url when it had to cut
+// anything.
+func Truncate(text, url string, reserve int) string {
+	budget := TelegramMessageLimit - reserve
+	if len(text) <= budget {
+		return text
+	}
+	suffix := fmt.Sprintf("…\nЧитать полностью: %s", url)
+	limit := budget - len(suffix)
+	if limit < 0 {
+		limit = 0
+	}
+	for limit > 0 && !utf8.RuneStart(text[limit]) {
+		limit--
+	}
+	return text[:limit] + suffix
+}
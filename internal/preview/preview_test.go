@@ -0,0 +1,126 @@
+package preview
+
+import (
+	"net"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+)
+
+func TestTruncateShortTextUnchanged(t *testing.T) {
+	text := "short text"
+	if got := Truncate(text, "https://example.com", 0); got != text {
+		t.Errorf("Truncate(%q) = %q, want unchanged", text, got)
+	}
+}
+
+func TestTruncateRespectsReserve(t *testing.T) {
+	text := strings.Repeat("a", TelegramMessageLimit)
+	header := strings.Repeat("h", 100)
+
+	got := Truncate(text, "https://example.com", len(header))
+	if len(header)+len(got) > TelegramMessageLimit {
+		t.Errorf("header(%d) + Truncate result(%d) = %d, exceeds TelegramMessageLimit(%d)",
+			len(header), len(got), len(header)+len(got), TelegramMessageLimit)
+	}
+}
+
+func TestTruncateCutsAtRuneBoundary(t *testing.T) {
+	// A 1-byte prefix followed by Cyrillic text where every rune is 2 bytes,
+	// so any even byte offset lands mid-rune; with this url the cut falls at
+	// byte 4040 and truncation has to back off to a boundary.
+	text := "a" + strings.Repeat("привет", (TelegramMessageLimit/len("привет"))+10)
+
+	got := Truncate(text, "https://example.com", 0)
+	if !utf8.ValidString(got) {
+		t.Errorf("Truncate produced invalid UTF-8: %q", got)
+	}
+}
+
+func TestTruncateAppendsReadMoreLink(t *testing.T) {
+	text := strings.Repeat("a", TelegramMessageLimit+1)
+	url := "https://example.com/article"
+
+	got := Truncate(text, url, 0)
+	if !strings.Contains(got, url) {
+		t.Errorf("Truncate(%q) = %q, want it to contain the read-more url", text, got)
+	}
+	if len(got) > TelegramMessageLimit {
+		t.Errorf("Truncate result length %d exceeds TelegramMessageLimit %d", len(got), TelegramMessageLimit)
+	}
+}
+
+func TestCollapseWhitespace(t *testing.T) {
+	in := "  foo   bar  \n\n\n\nbaz  \n\n\n"
+	want := "foo bar\n\nbaz"
+	if got := collapseWhitespace(in); got != want {
+		t.Errorf("collapseWhitespace(%q) = %q, want %q", in, got, want)
+	}
+}
+
+func TestExtractText(t *testing.T) {
+	doc, err := html.Parse(strings.NewReader(`
+		<html><body>
+			<p>Hello <a href="https://example.com/x">world</a></p>
+			<script>ignored()</script>
+			<div>Second block</div>
+		</body></html>`))
+	if err != nil {
+		t.Fatalf("html.Parse: %s", err)
+	}
+
+	got := extractText(findBody(doc))
+	for _, want := range []string{"Hello", "world (https://example.com/x)", "Second block"} {
+		if !strings.Contains(got, want) {
+			t.Errorf("extractText() = %q, want it to contain %q", got, want)
+		}
+	}
+	if strings.Contains(got, "ignored()") {
+		t.Errorf("extractText() = %q, want script contents dropped", got)
+	}
+}
+
+func TestIsDisallowedIP(t *testing.T) {
+	tests := []struct {
+		ip   string
+		want bool
+	}{
+		{"127.0.0.1", true},
+		{"::1", true},
+		{"169.254.1.1", true},
+		{"10.0.0.5", true},
+		{"192.168.1.1", true},
+		{"172.16.0.1", true},
+		{"0.0.0.0", true},
+		{"8.8.8.8", false},
+		{"93.184.216.34", false},
+	}
+
+	for _, tt := range tests {
+		ip := net.ParseIP(tt.ip)
+		if ip == nil {
+			t.Fatalf("net.ParseIP(%q) returned nil", tt.ip)
+		}
+		if got := isDisallowedIP(ip); got != tt.want {
+			t.Errorf("isDisallowedIP(%q) = %v, want %v", tt.ip, got, tt.want)
+		}
+	}
+}
+
+func TestCheckFetchableURLRejectsBadSchemes(t *testing.T) {
+	for _, url := range []string{"file:///etc/passwd", "ftp://example.com/x", "javascript:alert(1)"} {
+		if err := checkFetchableURL(url); err == nil {
+			t.Errorf("checkFetchableURL(%q) = nil, want error for disallowed scheme", url)
+		}
+	}
+}
+
+func TestCheckFetchableURLRejectsLoopback(t *testing.T) {
+	for _, url := range []string{"http://127.0.0.1/", "http://localhost/"} {
+		if err := checkFetchableURL(url); err == nil {
+			t.Errorf("checkFetchableURL(%q) = nil, want error for loopback host", url)
+		}
+	}
+}
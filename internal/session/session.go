@@ -0,0 +1,61 @@
+// Package session tracks which posts are currently offered for review so
+// that operators can have several pending decisions outstanding at once
+// without one clobbering another.
+package session
+
+import (
+	"sync"
+
+	"github.com/mehanizm/airtable"
+)
+
+// Offer is a post offered for review, waiting on an Approve/Reject/Skip
+// decision from whichever operator it was sent to.
+type Offer struct {
+	Record    *airtable.Record
+	ChatID    int64
+	MessageID int
+}
+
+// Store holds outstanding offers keyed by Airtable record ID, guarded by a
+// RWMutex: lookups run concurrently, while SetOffer and ClearOffer take the
+// lock exclusively for every post.
+type Store struct {
+	mu     sync.RWMutex
+	offers map[string]*Offer
+}
+
+// New creates an empty Store.
+func New() *Store {
+	return &Store{
+		offers: make(map[string]*Offer),
+	}
+}
+
+// Offer returns the pending offer for recordID, if any.
+func (s *Store) Offer(recordID string) (*Offer, bool) {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	o, ok := s.offers[recordID]
+	return o, ok
+}
+
+// HasOffer reports whether recordID already has a pending offer.
+func (s *Store) HasOffer(recordID string) bool {
+	_, ok := s.Offer(recordID)
+	return ok
+}
+
+// SetOffer records that record was offered to chatID in messageID.
+func (s *Store) SetOffer(chatID int64, record *airtable.Record, messageID int) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.offers[record.ID] = &Offer{Record: record, ChatID: chatID, MessageID: messageID}
+}
+
+// ClearOffer forgets the pending offer for recordID.
+func (s *Store) ClearOffer(recordID string) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.offers, recordID)
+}
@@ -0,0 +1,237 @@
+// Package store persists operator decisions in a SQLite database so that
+// skipped/approved/rejected posts are not re-offered after a restart and
+// so that decisions can be audited later.
+package store
+
+import (
+	"database/sql"
+	"fmt"
+	"time"
+
+	_ "modernc.org/sqlite"
+)
+
+// Decision is the outcome an operator chose for a post.
+type Decision string
+
+const (
+	DecisionApprove Decision = "approve"
+	DecisionReject  Decision = "reject"
+	DecisionSkip    Decision = "skip"
+)
+
+// DecisionRecord is a single row of the decisions table.
+type DecisionRecord struct {
+	GUID      string
+	ChatID    int64
+	Decision  Decision
+	DecidedAt time.Time
+}
+
+// Store wraps a SQLite database holding the decisions table.
+type Store struct {
+	db *sql.DB
+}
+
+// Open opens (creating if necessary) the SQLite database at path and
+// ensures the schema is in place.
+func Open(path string) (*Store, error) {
+	db, err := sql.Open("sqlite", path)
+	if err != nil {
+		return nil, fmt.Errorf("open sqlite: %w", err)
+	}
+	// modernc.org/sqlite has no built-in connection pooling story: the
+	// callback handler and the subscriptions poller write concurrently from
+	// separate goroutines, and a second connection hitting a locked
+	// database returns SQLITE_BUSY instead of waiting for it. Force a
+	// single connection so writes serialize instead of failing.
+	db.SetMaxOpenConns(1)
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS decisions (
+			guid       TEXT NOT NULL,
+			chat_id    INTEGER NOT NULL,
+			decision   TEXT NOT NULL,
+			decided_at DATETIME NOT NULL
+		)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create decisions table: %w", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS subscriptions (
+			chat_id          INTEGER PRIMARY KEY,
+			interval_seconds INTEGER NOT NULL,
+			paused           BOOLEAN NOT NULL DEFAULT 0,
+			last_sent_at     DATETIME
+		)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create subscriptions table: %w", err)
+	}
+	if _, err := db.Exec(`
+		CREATE TABLE IF NOT EXISTS previews (
+			guid      TEXT PRIMARY KEY,
+			text      TEXT NOT NULL,
+			cached_at DATETIME NOT NULL
+		)`); err != nil {
+		db.Close()
+		return nil, fmt.Errorf("create previews table: %w", err)
+	}
+	return &Store{db: db}, nil
+}
+
+// Close closes the underlying database.
+func (s *Store) Close() error {
+	return s.db.Close()
+}
+
+// Has reports whether guid already has a recorded decision.
+func (s *Store) Has(guid string) (bool, error) {
+	var exists bool
+	err := s.db.QueryRow(`SELECT EXISTS(SELECT 1 FROM decisions WHERE guid = ?)`, guid).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("check decision for %s: %w", guid, err)
+	}
+	return exists, nil
+}
+
+// Record stores a decision for guid made by chatID at decidedAt.
+func (s *Store) Record(guid string, chatID int64, decision Decision, decidedAt time.Time) error {
+	_, err := s.db.Exec(
+		`INSERT INTO decisions (guid, chat_id, decision, decided_at) VALUES (?, ?, ?, ?)`,
+		guid, chatID, string(decision), decidedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("record decision for %s: %w", guid, err)
+	}
+	return nil
+}
+
+// RecentDecisions returns the last n decisions made by chatID, most recent first.
+func (s *Store) RecentDecisions(chatID int64, n int) ([]DecisionRecord, error) {
+	rows, err := s.db.Query(
+		`SELECT guid, chat_id, decision, decided_at FROM decisions
+		 WHERE chat_id = ? ORDER BY decided_at DESC LIMIT ?`,
+		chatID, n,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("query recent decisions for chat %d: %w", chatID, err)
+	}
+	defer rows.Close()
+
+	var records []DecisionRecord
+	for rows.Next() {
+		var r DecisionRecord
+		var decision string
+		if err := rows.Scan(&r.GUID, &r.ChatID, &decision, &r.DecidedAt); err != nil {
+			return nil, fmt.Errorf("scan decision row: %w", err)
+		}
+		r.Decision = Decision(decision)
+		records = append(records, r)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate decisions for chat %d: %w", chatID, err)
+	}
+	return records, nil
+}
+
+// Subscription is a chat's push-mode configuration.
+type Subscription struct {
+	ChatID     int64
+	Interval   time.Duration
+	Paused     bool
+	LastSentAt time.Time
+}
+
+// UpsertSubscription creates chatID's subscription, or updates its
+// interval and un-pauses it if one already exists.
+func (s *Store) UpsertSubscription(chatID int64, interval time.Duration) error {
+	_, err := s.db.Exec(`
+		INSERT INTO subscriptions (chat_id, interval_seconds, paused) VALUES (?, ?, 0)
+		ON CONFLICT(chat_id) DO UPDATE SET interval_seconds = excluded.interval_seconds, paused = 0`,
+		chatID, int64(interval.Seconds()),
+	)
+	if err != nil {
+		return fmt.Errorf("upsert subscription for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+// DeleteSubscription removes chatID's subscription, if any.
+func (s *Store) DeleteSubscription(chatID int64) error {
+	if _, err := s.db.Exec(`DELETE FROM subscriptions WHERE chat_id = ?`, chatID); err != nil {
+		return fmt.Errorf("delete subscription for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+// SetSubscriptionPaused pauses or resumes chatID's subscription.
+func (s *Store) SetSubscriptionPaused(chatID int64, paused bool) error {
+	res, err := s.db.Exec(`UPDATE subscriptions SET paused = ? WHERE chat_id = ?`, paused, chatID)
+	if err != nil {
+		return fmt.Errorf("set paused for chat %d: %w", chatID, err)
+	}
+	if n, err := res.RowsAffected(); err == nil && n == 0 {
+		return fmt.Errorf("chat %d has no subscription", chatID)
+	}
+	return nil
+}
+
+// MarkSubscriptionSent records that a post was just pushed to chatID.
+func (s *Store) MarkSubscriptionSent(chatID int64, at time.Time) error {
+	if _, err := s.db.Exec(`UPDATE subscriptions SET last_sent_at = ? WHERE chat_id = ?`, at, chatID); err != nil {
+		return fmt.Errorf("mark subscription sent for chat %d: %w", chatID, err)
+	}
+	return nil
+}
+
+// CachedPreview returns the previously rendered preview for guid, if any.
+func (s *Store) CachedPreview(guid string) (string, bool, error) {
+	var text string
+	err := s.db.QueryRow(`SELECT text FROM previews WHERE guid = ?`, guid).Scan(&text)
+	if err == sql.ErrNoRows {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("load cached preview for %s: %w", guid, err)
+	}
+	return text, true, nil
+}
+
+// CachePreview stores text as guid's rendered preview, replacing any
+// previous one.
+func (s *Store) CachePreview(guid, text string, cachedAt time.Time) error {
+	_, err := s.db.Exec(`
+		INSERT INTO previews (guid, text, cached_at) VALUES (?, ?, ?)
+		ON CONFLICT(guid) DO UPDATE SET text = excluded.text, cached_at = excluded.cached_at`,
+		guid, text, cachedAt,
+	)
+	if err != nil {
+		return fmt.Errorf("cache preview for %s: %w", guid, err)
+	}
+	return nil
+}
+
+// ListSubscriptions returns every subscription, paused or not.
+func (s *Store) ListSubscriptions() ([]Subscription, error) {
+	rows, err := s.db.Query(`SELECT chat_id, interval_seconds, paused, last_sent_at FROM subscriptions`)
+	if err != nil {
+		return nil, fmt.Errorf("list subscriptions: %w", err)
+	}
+	defer rows.Close()
+
+	var subs []Subscription
+	for rows.Next() {
+		var sub Subscription
+		var seconds int64
+		var lastSentAt sql.NullTime
+		if err := rows.Scan(&sub.ChatID, &seconds, &sub.Paused, &lastSentAt); err != nil {
+			return nil, fmt.Errorf("scan subscription row: %w", err)
+		}
+		sub.Interval = time.Duration(seconds) * time.Second
+		sub.LastSentAt = lastSentAt.Time
+		subs = append(subs, sub)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("iterate subscriptions: %w", err)
+	}
+	return subs, nil
+}
@@ -0,0 +1,126 @@
+// Package subscriptions pushes un-reviewed posts to operators on a
+// schedule instead of waiting for them to run /getpost.
+package subscriptions
+
+import (
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/IShamraI/post-approver-bot/internal/handlers"
+	"github.com/IShamraI/post-approver-bot/internal/preview"
+	"github.com/IShamraI/post-approver-bot/internal/session"
+	"github.com/IShamraI/post-approver-bot/internal/store"
+	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
+	"github.com/mehanizm/airtable"
+)
+
+const maxBackoff = 30 * time.Minute
+
+// Manager runs the background push loop and persists subscriptions via store.
+type Manager struct {
+	bot          *tgbotapi.BotAPI
+	table        *airtable.Table
+	store        *store.Store
+	sessions     *session.Store
+	preview      *preview.Fetcher
+	pollInterval time.Duration
+}
+
+// NewManager creates a Manager that checks for due subscriptions every
+// pollInterval. fetcher may be nil, in which case pushed posts carry no preview.
+func NewManager(bot *tgbotapi.BotAPI, table *airtable.Table, st *store.Store, sessions *session.Store, fetcher *preview.Fetcher, pollInterval time.Duration) *Manager {
+	return &Manager{
+		bot:          bot,
+		table:        table,
+		store:        st,
+		sessions:     sessions,
+		preview:      fetcher,
+		pollInterval: pollInterval,
+	}
+}
+
+// Subscribe starts (or updates the cadence of) chatID's push subscription.
+func (m *Manager) Subscribe(chatID int64, interval time.Duration) error {
+	return m.store.UpsertSubscription(chatID, interval)
+}
+
+// Unsubscribe stops pushing posts to chatID.
+func (m *Manager) Unsubscribe(chatID int64) error {
+	return m.store.DeleteSubscription(chatID)
+}
+
+// Pause temporarily stops pushing posts to chatID without forgetting the subscription.
+func (m *Manager) Pause(chatID int64) error {
+	return m.store.SetSubscriptionPaused(chatID, true)
+}
+
+// Resume undoes Pause.
+func (m *Manager) Resume(chatID int64) error {
+	return m.store.SetSubscriptionPaused(chatID, false)
+}
+
+// Run polls for due subscriptions until stop is closed, backing off
+// exponentially whenever Airtable errors instead of taking the bot down.
+func (m *Manager) Run(stop <-chan struct{}) {
+	backoff := m.pollInterval
+	ticker := time.NewTicker(backoff)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-stop:
+			return
+		case <-ticker.C:
+			if err := m.tick(); err != nil {
+				log.Printf("subscriptions: poll failed: %s", err)
+				backoff *= 2
+				if backoff > maxBackoff {
+					backoff = maxBackoff
+				}
+			} else {
+				backoff = m.pollInterval
+			}
+			ticker.Reset(backoff)
+		}
+	}
+}
+
+func (m *Manager) tick() error {
+	subs, err := m.store.ListSubscriptions()
+	if err != nil {
+		return fmt.Errorf("list subscriptions: %w", err)
+	}
+
+	now := time.Now()
+	for _, sub := range subs {
+		if sub.Paused {
+			continue
+		}
+		if !sub.LastSentAt.IsZero() && now.Sub(sub.LastSentAt) < sub.Interval {
+			continue
+		}
+		post, err := handlers.SelectPost(m.table, m.store, m.sessions)
+		if err != nil {
+			// The Airtable fetch backing SelectPost isn't chat-specific, so a
+			// failure here means every remaining subscriber would fail the
+			// same way: abort the tick and let Run back off.
+			return fmt.Errorf("select post for chat %d: %w", sub.ChatID, err)
+		}
+		if post == nil {
+			continue
+		}
+		if err := m.push(sub.ChatID, now, post); err != nil {
+			log.Printf("subscriptions: push to chat %d failed: %s", sub.ChatID, err)
+		}
+	}
+	return nil
+}
+
+func (m *Manager) push(chatID int64, now time.Time, post *airtable.Record) error {
+	if err := handlers.SendPost(m.bot, m.sessions, m.store, m.preview, chatID, post); err != nil {
+		return err
+	}
+
+	return m.store.MarkSubscriptionSent(chatID, now)
+}
@@ -5,25 +5,40 @@ import (
 	"log"
 	"time"
 
-	ttlcache "github.com/jellydator/ttlcache/v3"
-
-	"github.com/IShamraI/post-approver-bot/internal/buttons"
 	"github.com/IShamraI/post-approver-bot/internal/env"
+	"github.com/IShamraI/post-approver-bot/internal/handlers"
 	"github.com/IShamraI/post-approver-bot/internal/helpers"
+	"github.com/IShamraI/post-approver-bot/internal/preview"
+	"github.com/IShamraI/post-approver-bot/internal/session"
+	"github.com/IShamraI/post-approver-bot/internal/store"
+	"github.com/IShamraI/post-approver-bot/internal/subscriptions"
 	"github.com/mehanizm/airtable"
 
 	tgbotapi "github.com/go-telegram-bot-api/telegram-bot-api/v5"
 )
 
-var oneTimePostKB = tgbotapi.NewOneTimeReplyKeyboard(
-	tgbotapi.NewKeyboardButtonRow(
-		tgbotapi.NewKeyboardButton(buttons.ApproveButton.Text()),
-		tgbotapi.NewKeyboardButton(buttons.RejectButton.Text()),
-	),
-	tgbotapi.NewKeyboardButtonRow(
-		tgbotapi.NewKeyboardButton(buttons.SkipButton.Text()),
-	),
-)
+// subscriptionPollInterval is how often the push loop checks for due subscriptions.
+const subscriptionPollInterval = 30 * time.Second
+
+// previewUserAgent identifies the bot to the sites it fetches previews from.
+const previewUserAgent = "post-approver-bot/1.0 (+https://github.com/IShamraI/post-approver-bot)"
+
+func newRouter() *handlers.Router {
+	router := handlers.NewRouter()
+	router.HandleCommand("start", handlers.Start)
+	router.HandleCommand("getpost", handlers.GetPost)
+	router.HandleCommand("history", handlers.History)
+	router.HandleCommand("subscribe", handlers.Subscribe)
+	router.HandleCommand("unsubscribe", handlers.Unsubscribe)
+	router.HandleCommand("pause", handlers.Pause)
+	router.HandleCommand("resume", handlers.Resume)
+	router.HandleCommand("preview", handlers.Preview)
+	router.HandleCommand("help", handlers.Help)
+	router.HandleCommand("status", handlers.Status)
+	router.UnknownCommand(handlers.UnknownCommand)
+
+	return router
+}
 
 func main() {
 	// Initialize Telegram bot
@@ -41,9 +56,28 @@ func main() {
 	}
 	table := client.GetTable(envVars.AirtableBaseId, envVars.AirtableTableName)
 
+	// Initialize the decisions store
+	decisions, err := store.Open(envVars.StorePath)
+	if err != nil {
+		log.Panic(err)
+	}
+	defer decisions.Close()
+
+	// Initialize the preview fetcher, if enabled
+	var previewFetcher *preview.Fetcher
+	if envVars.PreviewEnabled {
+		previewFetcher = preview.NewFetcher(preview.DefaultTimeout, preview.DefaultMaxBytes, previewUserAgent)
+	}
+
 	// Set up bot commands
 	commands := []tgbotapi.BotCommand{
 		{Command: "getpost", Description: "Get post"},
+		{Command: "history", Description: "Show recent decisions"},
+		{Command: "subscribe", Description: "Get posts pushed automatically"},
+		{Command: "unsubscribe", Description: "Stop getting posts pushed"},
+		{Command: "pause", Description: "Pause the push subscription"},
+		{Command: "resume", Description: "Resume the push subscription"},
+		{Command: "preview", Description: "Preview a post by its guid"},
 		// {Command: "getstats", Description: "Get statistics"},
 	}
 	setCommands := tgbotapi.NewSetMyCommands(commands...)
@@ -54,93 +88,54 @@ func main() {
 
 	log.Printf("Authorized on account %s", bot.Self.UserName)
 
-	cache := ttlcache.New[string, bool](
-		ttlcache.WithTTL[string, bool](24 * time.Hour),
-	)
+	sessions := session.New()
+	subs := subscriptions.NewManager(bot, table, decisions, sessions, previewFetcher, subscriptionPollInterval)
+	stopSubs := make(chan struct{})
+	defer close(stopSubs)
+	go subs.Run(stopSubs)
 
-	go cache.Start() // starts automatic expired item deletion
+	router := newRouter()
 
 	u := tgbotapi.NewUpdate(0)
 	u.Timeout = 60
 
 	updates := bot.GetUpdatesChan(u)
 
-	var currentPost *airtable.Record
-
 	for update := range updates {
 		if !helpers.IDContains(envVars.TelegramWhiteList, update.FromChat().ID) {
 			log.Printf("got update from unknown user: %+v", update)
 			continue
 		}
-		// Create a new MessageConfig. We don't have text yet,
-		// so we leave it empty.
-		msg := tgbotapi.NewMessage(update.Message.Chat.ID, "")
 
-		if update.Message.IsCommand() {
-			log.Printf("got command: %s", update.Message.Command())
+		ctx := &handlers.Context{
+			Bot:           bot,
+			Update:        update,
+			Sessions:      sessions,
+			Decisions:     decisions,
+			Table:         table,
+			Subscriptions: subs,
+			Preview:       previewFetcher,
+			Logger:        log.Default(),
+		}
 
-			// Extract the command from the Message.
-			switch update.Message.Command() {
-			case "start":
-				msg.Text = "Hi!"
-			case "getpost":
-				records, err := table.GetRecords().
-					FromView("view_1").
-					WithFilterFormula("AND({ToInvistigate} = 0, {IsApproved} = 0, {IsRejected} = 0)").
-					ReturnFields("Title", "guid").
-					InStringFormat("Europe/Moscow", "ru").
-					Do()
-				if err != nil {
-					log.Panic(err)
-				}
-				for i, record := range records.Records {
-					if cache.Has(record.Fields["guid"].(string)) {
-						continue
-					}
-					currentPost = records.Records[i]
-					break
-				}
-				msg.Text = fmt.Sprintf("Пост: %s\n%s", currentPost.Fields["Title"], currentPost.Fields["guid"])
-				msg.ReplyMarkup = oneTimePostKB
-			case "help":
-				msg.Text = "I understand /sayhi and /status."
-			case "status":
-				msg.Text = "I'm ok."
-			default:
-				msg.Text = "I don't know that command"
-			}
-		} else {
+		var err error
+		switch {
+		case update.CallbackQuery != nil:
+			log.Printf("got callback: %s", update.CallbackQuery.Data)
+			err = handlers.HandleCallback(ctx)
+		case update.Message != nil && update.Message.IsCommand():
+			log.Printf("got command: %s", update.Message.Command())
+			err = router.Dispatch(ctx)
+		case update.Message != nil:
 			log.Printf("got text: %s", update.Message.Text)
-			switch update.Message.Text {
-			case buttons.ApproveButton.Text():
-				msg.Text = "Пост принят"
-				_, err := currentPost.UpdateRecordPartial(map[string]any{"IsApproved": true, "IsRejected": false, "ToInvistigate": false})
-				if err != nil {
-					log.Printf("error while approving: %s", err)
-					msg.Text = fmt.Sprintf("Произошла ошибка: %s", err)
-					currentPost = nil
-				}
-			case buttons.RejectButton.Text():
-				msg.Text = "Пост отклонен"
-				_, err := currentPost.UpdateRecordPartial(map[string]any{"IsRejected": true, "IsApproved": false, "ToInvistigate": false})
-				if err != nil {
-					log.Printf("error while rejecting: %s", err)
-					msg.Text = fmt.Sprintf("Произошла ошибка: %s", err)
-					currentPost = nil
-				}
-			case buttons.SkipButton.Text():
-				msg.Text = "Пост пропущен"
-				cache.Set(currentPost.Fields["guid"].(string), true, ttlcache.DefaultTTL)
-				currentPost = nil
-			default:
-				msg.Text = "Кнопка не поддерживается"
-				currentPost = nil
-			}
-
+			err = ctx.Reply("I don't know that command", nil)
 		}
 
-		if _, err := bot.Send(msg); err != nil {
-			log.Panic(err)
+		if err != nil {
+			log.Printf("handler error: %s", err)
+			if _, sendErr := bot.Send(tgbotapi.NewMessage(ctx.ChatID(), fmt.Sprintf("Произошла ошибка: %s", err))); sendErr != nil {
+				log.Printf("error while reporting handler error: %s", sendErr)
+			}
 		}
 	}
 }